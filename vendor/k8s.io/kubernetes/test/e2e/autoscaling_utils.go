@@ -19,11 +19,18 @@ package e2e
 import (
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/apis/apps"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/client/cache"
 	clientset "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+	"k8s.io/kubernetes/pkg/fields"
+	"k8s.io/kubernetes/pkg/runtime"
 	"k8s.io/kubernetes/pkg/util/intstr"
+	"k8s.io/kubernetes/pkg/watch"
 	"k8s.io/kubernetes/test/e2e/framework"
 	testutils "k8s.io/kubernetes/test/utils"
 
@@ -46,12 +53,14 @@ const (
 	rcIsNil                         = "ERROR: replicationController = nil"
 	deploymentIsNil                 = "ERROR: deployment = nil"
 	rsIsNil                         = "ERROR: replicaset = nil"
+	statefulSetIsNil                = "ERROR: statefulset = nil"
 	invalidKind                     = "ERROR: invalid workload kind for resource consumer"
 	customMetricName                = "QPS"
+	kindStatefulSet                 = "StatefulSet"
 )
 
 /*
-ResourceConsumer is a tool for testing. It helps create specified usage of CPU or memory (Warning: memory not supported)
+ResourceConsumer is a tool for testing. It helps create specified usage of CPU or memory.
 typical use case:
 rc.ConsumeCPU(600)
 // ... check your assumption here
@@ -60,15 +69,17 @@ rc.ConsumeCPU(300)
 */
 type ResourceConsumer struct {
 	name                     string
-	controllerName           string
+	controller               *ResourceConsumerController
 	kind                     string
 	framework                *framework.Framework
+	wg                       sync.WaitGroup
 	cpu                      chan int
 	mem                      chan int
-	customMetric             chan int
+	customMetricMu           sync.Mutex
+	customMetric             map[string]chan int
 	stopCPU                  chan int
 	stopMem                  chan int
-	stopCustomMetric         chan int
+	stopCustomMetric         map[string]chan int
 	consumptionTimeInSeconds int
 	sleepTime                time.Duration
 	requestSizeInMillicores  int
@@ -76,14 +87,14 @@ type ResourceConsumer struct {
 	requestSizeCustomMetric  int
 }
 
-func NewDynamicResourceConsumer(name, kind string, replicas, initCPUTotal, initMemoryTotal, initCustomMetric int, cpuLimit, memLimit int64, f *framework.Framework) *ResourceConsumer {
-	return newResourceConsumer(name, kind, replicas, initCPUTotal, initMemoryTotal, initCustomMetric, dynamicConsumptionTimeInSeconds,
+func NewDynamicResourceConsumer(name, kind string, replicas, initCPUTotal, initMemoryTotal int, initCustomMetrics map[string]int, cpuLimit, memLimit int64, f *framework.Framework) *ResourceConsumer {
+	return newResourceConsumer(name, kind, replicas, initCPUTotal, initMemoryTotal, initCustomMetrics, dynamicConsumptionTimeInSeconds,
 		dynamicRequestSizeInMillicores, dynamicRequestSizeInMegabytes, dynamicRequestSizeCustomMetric, cpuLimit, memLimit, f)
 }
 
 // TODO this still defaults to replication controller
 func NewStaticResourceConsumer(name string, replicas, initCPUTotal, initMemoryTotal, initCustomMetric int, cpuLimit, memLimit int64, f *framework.Framework) *ResourceConsumer {
-	return newResourceConsumer(name, kindRC, replicas, initCPUTotal, initMemoryTotal, initCustomMetric, staticConsumptionTimeInSeconds,
+	return newResourceConsumer(name, kindRC, replicas, initCPUTotal, initMemoryTotal, map[string]int{customMetricName: initCustomMetric}, staticConsumptionTimeInSeconds,
 		initCPUTotal/replicas, initMemoryTotal/replicas, initCustomMetric/replicas, cpuLimit, memLimit, f)
 }
 
@@ -91,24 +102,26 @@ func NewStaticResourceConsumer(name string, replicas, initCPUTotal, initMemoryTo
 NewResourceConsumer creates new ResourceConsumer
 initCPUTotal argument is in millicores
 initMemoryTotal argument is in megabytes
+initCustomMetrics maps a custom-metric name to its initial total value; a consumer can drive
+any number of named metrics concurrently
 memLimit argument is in megabytes, memLimit is a maximum amount of memory that can be consumed by a single pod
 cpuLimit argument is in millicores, cpuLimit is a maximum amount of cpu that can be consumed by a single pod
 */
-func newResourceConsumer(name, kind string, replicas, initCPUTotal, initMemoryTotal, initCustomMetric, consumptionTimeInSeconds, requestSizeInMillicores,
+func newResourceConsumer(name, kind string, replicas, initCPUTotal, initMemoryTotal int, initCustomMetrics map[string]int, consumptionTimeInSeconds, requestSizeInMillicores,
 	requestSizeInMegabytes int, requestSizeCustomMetric int, cpuLimit, memLimit int64, f *framework.Framework) *ResourceConsumer {
 
-	runServiceAndWorkloadForResourceConsumer(f.ClientSet, f.Namespace.Name, name, kind, replicas, cpuLimit, memLimit)
+	controller := runServiceAndWorkloadForResourceConsumer(f.ClientSet, f.Namespace.Name, name, kind, replicas, cpuLimit, memLimit)
 	rc := &ResourceConsumer{
 		name:                     name,
-		controllerName:           name + "-ctrl",
+		controller:               controller,
 		kind:                     kind,
 		framework:                f,
 		cpu:                      make(chan int),
 		mem:                      make(chan int),
-		customMetric:             make(chan int),
+		customMetric:             make(map[string]chan int),
 		stopCPU:                  make(chan int),
 		stopMem:                  make(chan int),
-		stopCustomMetric:         make(chan int),
+		stopCustomMetric:         make(map[string]chan int),
 		consumptionTimeInSeconds: consumptionTimeInSeconds,
 		sleepTime:                time.Duration(consumptionTimeInSeconds) * time.Second,
 		requestSizeInMillicores:  requestSizeInMillicores,
@@ -116,13 +129,17 @@ func newResourceConsumer(name, kind string, replicas, initCPUTotal, initMemoryTo
 		requestSizeCustomMetric:  requestSizeCustomMetric,
 	}
 
+	rc.wg.Add(1)
 	go rc.makeConsumeCPURequests()
 	rc.ConsumeCPU(initCPUTotal)
 
+	rc.wg.Add(1)
 	go rc.makeConsumeMemRequests()
 	rc.ConsumeMem(initMemoryTotal)
-	go rc.makeConsumeCustomMetric()
-	rc.ConsumeCustomMetric(initCustomMetric)
+
+	for metricName, metricValue := range initCustomMetrics {
+		rc.ConsumeCustomMetricNamed(metricName, metricValue)
+	}
 	return rc
 }
 
@@ -138,13 +155,40 @@ func (rc *ResourceConsumer) ConsumeMem(megabytes int) {
 	rc.mem <- megabytes
 }
 
-// ConsumeMem consumes given number of custom metric
+// ConsumeCustomMetric consumes given amount of the default ("QPS") custom metric
 func (rc *ResourceConsumer) ConsumeCustomMetric(amount int) {
-	framework.Logf("RC %s: consume custom metric %v in total", rc.name, amount)
-	rc.customMetric <- amount
+	rc.ConsumeCustomMetricNamed(customMetricName, amount)
+}
+
+// ConsumeCustomMetricNamed consumes given amount of the named custom metric, starting that
+// metric's request-sending goroutine on first use if it wasn't already driven at construction
+// time via NewDynamicResourceConsumer's initCustomMetrics.
+func (rc *ResourceConsumer) ConsumeCustomMetricNamed(name string, amount int) {
+	metric := rc.ensureCustomMetricStarted(name)
+	framework.Logf("RC %s: consume custom metric %s: %v in total", rc.name, name, amount)
+	metric <- amount
+}
+
+// ensureCustomMetricStarted lazily registers name's channel/stop-channel pair and starts its
+// request-sending goroutine, so consuming a metric that wasn't passed to
+// NewDynamicResourceConsumer still works instead of blocking forever on a nil channel. It
+// returns the metric's consumption channel for the caller to send on.
+func (rc *ResourceConsumer) ensureCustomMetricStarted(name string) chan int {
+	rc.customMetricMu.Lock()
+	defer rc.customMetricMu.Unlock()
+	if metric, ok := rc.customMetric[name]; ok {
+		return metric
+	}
+	metric := make(chan int)
+	rc.customMetric[name] = metric
+	rc.stopCustomMetric[name] = make(chan int)
+	rc.wg.Add(1)
+	go rc.makeConsumeCustomMetric(name)
+	return metric
 }
 
 func (rc *ResourceConsumer) makeConsumeCPURequests() {
+	defer rc.wg.Done()
 	defer GinkgoRecover()
 	sleepTime := time.Duration(0)
 	millicores := 0
@@ -163,6 +207,7 @@ func (rc *ResourceConsumer) makeConsumeCPURequests() {
 }
 
 func (rc *ResourceConsumer) makeConsumeMemRequests() {
+	defer rc.wg.Done()
 	defer GinkgoRecover()
 	sleepTime := time.Duration(0)
 	megabytes := 0
@@ -180,19 +225,27 @@ func (rc *ResourceConsumer) makeConsumeMemRequests() {
 	}
 }
 
-func (rc *ResourceConsumer) makeConsumeCustomMetric() {
+// makeConsumeCustomMetric must be started with rc.customMetric[name]/rc.stopCustomMetric[name]
+// already registered; it captures both channels once so it never indexes the maps again,
+// since they may grow concurrently as other metric names are lazily started.
+func (rc *ResourceConsumer) makeConsumeCustomMetric(name string) {
+	defer rc.wg.Done()
 	defer GinkgoRecover()
+	rc.customMetricMu.Lock()
+	metric, stop := rc.customMetric[name], rc.stopCustomMetric[name]
+	rc.customMetricMu.Unlock()
+
 	sleepTime := time.Duration(0)
 	delta := 0
 	for {
 		select {
-		case delta := <-rc.customMetric:
-			framework.Logf("RC %s: setting bump of metric %s to %d in total", rc.name, customMetricName, delta)
+		case delta = <-metric:
+			framework.Logf("RC %s: setting bump of metric %s to %d in total", rc.name, name, delta)
 		case <-time.After(sleepTime):
-			framework.Logf("RC %s: sending request to consume %d of custom metric %s", rc.name, delta, customMetricName)
-			rc.sendConsumeCustomMetric(delta)
+			framework.Logf("RC %s: sending request to consume %d of custom metric %s", rc.name, delta, name)
+			rc.sendConsumeCustomMetric(name, delta)
 			sleepTime = rc.sleepTime
-		case <-rc.stopCustomMetric:
+		case <-stop:
 			return
 		}
 	}
@@ -202,7 +255,7 @@ func (rc *ResourceConsumer) sendConsumeCPURequest(millicores int) {
 	proxyRequest, err := framework.GetServicesProxyRequest(rc.framework.ClientSet, rc.framework.ClientSet.Core().RESTClient().Post())
 	framework.ExpectNoError(err)
 	req := proxyRequest.Namespace(rc.framework.Namespace.Name).
-		Name(rc.controllerName).
+		Name(rc.controller.name).
 		Suffix("ConsumeCPU").
 		Param("millicores", strconv.Itoa(millicores)).
 		Param("durationSec", strconv.Itoa(rc.consumptionTimeInSeconds)).
@@ -217,7 +270,7 @@ func (rc *ResourceConsumer) sendConsumeMemRequest(megabytes int) {
 	proxyRequest, err := framework.GetServicesProxyRequest(rc.framework.ClientSet, rc.framework.ClientSet.Core().RESTClient().Post())
 	framework.ExpectNoError(err)
 	req := proxyRequest.Namespace(rc.framework.Namespace.Name).
-		Name(rc.controllerName).
+		Name(rc.controller.name).
 		Suffix("ConsumeMem").
 		Param("megabytes", strconv.Itoa(megabytes)).
 		Param("durationSec", strconv.Itoa(rc.consumptionTimeInSeconds)).
@@ -228,13 +281,13 @@ func (rc *ResourceConsumer) sendConsumeMemRequest(megabytes int) {
 }
 
 // sendConsumeCustomMetric sends POST request for custom metric consumption
-func (rc *ResourceConsumer) sendConsumeCustomMetric(delta int) {
+func (rc *ResourceConsumer) sendConsumeCustomMetric(name string, delta int) {
 	proxyRequest, err := framework.GetServicesProxyRequest(rc.framework.ClientSet, rc.framework.ClientSet.Core().RESTClient().Post())
 	framework.ExpectNoError(err)
 	req := proxyRequest.Namespace(rc.framework.Namespace.Name).
-		Name(rc.controllerName).
+		Name(rc.controller.name).
 		Suffix("BumpMetric").
-		Param("metric", customMetricName).
+		Param("metric", name).
 		Param("delta", strconv.Itoa(delta)).
 		Param("durationSec", strconv.Itoa(rc.consumptionTimeInSeconds)).
 		Param("requestSizeMetrics", strconv.Itoa(rc.requestSizeCustomMetric))
@@ -266,65 +319,207 @@ func (rc *ResourceConsumer) GetReplicas() int {
 			framework.Failf(rsIsNil)
 		}
 		return int(rs.Status.Replicas)
+	case kindStatefulSet:
+		statefulSet, err := rc.framework.ClientSet.Apps().StatefulSets(rc.framework.Namespace.Name).Get(rc.name)
+		framework.ExpectNoError(err)
+		if statefulSet == nil {
+			framework.Failf(statefulSetIsNil)
+		}
+		return int(statefulSet.Status.Replicas)
 	default:
 		framework.Failf(invalidKind)
 	}
 	return 0
 }
 
-func (rc *ResourceConsumer) WaitForReplicas(desiredReplicas int) {
-	timeout := 15 * time.Minute
-	for start := time.Now(); time.Since(start) < timeout; time.Sleep(20 * time.Second) {
-		if desiredReplicas == rc.GetReplicas() {
-			framework.Logf("%s: current replicas number is equal to desired replicas number: %d", rc.kind, desiredReplicas)
-			return
-		} else {
-			framework.Logf("%s: current replicas number %d waiting to be %d", rc.kind, rc.GetReplicas(), desiredReplicas)
+// replicasListWatch returns a ListWatch scoped to rc's own workload object, along with an
+// empty instance of that object's type for use with cache.NewInformer.
+func (rc *ResourceConsumer) replicasListWatch() (*cache.ListWatch, runtime.Object) {
+	c := rc.framework.ClientSet
+	ns := rc.framework.Namespace.Name
+	fieldSelector := fields.OneTermEqualSelector("metadata.name", rc.name)
+	switch rc.kind {
+	case kindRC:
+		return &cache.ListWatch{
+			ListFunc: func(options api.ListOptions) (runtime.Object, error) {
+				options.FieldSelector = fieldSelector
+				return c.Core().ReplicationControllers(ns).List(options)
+			},
+			WatchFunc: func(options api.ListOptions) (watch.Interface, error) {
+				options.FieldSelector = fieldSelector
+				return c.Core().ReplicationControllers(ns).Watch(options)
+			},
+		}, &api.ReplicationController{}
+	case kindDeployment:
+		return &cache.ListWatch{
+			ListFunc: func(options api.ListOptions) (runtime.Object, error) {
+				options.FieldSelector = fieldSelector
+				return c.Extensions().Deployments(ns).List(options)
+			},
+			WatchFunc: func(options api.ListOptions) (watch.Interface, error) {
+				options.FieldSelector = fieldSelector
+				return c.Extensions().Deployments(ns).Watch(options)
+			},
+		}, &extensions.Deployment{}
+	case kindReplicaSet:
+		return &cache.ListWatch{
+			ListFunc: func(options api.ListOptions) (runtime.Object, error) {
+				options.FieldSelector = fieldSelector
+				return c.Extensions().ReplicaSets(ns).List(options)
+			},
+			WatchFunc: func(options api.ListOptions) (watch.Interface, error) {
+				options.FieldSelector = fieldSelector
+				return c.Extensions().ReplicaSets(ns).Watch(options)
+			},
+		}, &extensions.ReplicaSet{}
+	case kindStatefulSet:
+		return &cache.ListWatch{
+			ListFunc: func(options api.ListOptions) (runtime.Object, error) {
+				options.FieldSelector = fieldSelector
+				return c.Apps().StatefulSets(ns).List(options)
+			},
+			WatchFunc: func(options api.ListOptions) (watch.Interface, error) {
+				options.FieldSelector = fieldSelector
+				return c.Apps().StatefulSets(ns).Watch(options)
+			},
+		}, &apps.StatefulSet{}
+	default:
+		framework.Failf(invalidKind)
+	}
+	return nil, nil
+}
+
+// replicasOf extracts Status.Replicas from a watch event's object, whatever workload kind it is.
+func replicasOf(obj interface{}) int {
+	switch workload := obj.(type) {
+	case *api.ReplicationController:
+		return int(workload.Status.Replicas)
+	case *extensions.Deployment:
+		return int(workload.Status.Replicas)
+	case *extensions.ReplicaSet:
+		return int(workload.Status.Replicas)
+	case *apps.StatefulSet:
+		return int(workload.Status.Replicas)
+	}
+	return 0
+}
+
+// waitForReplicasEvent blocks until rc's workload is observed at desiredReplicas or timeout
+// elapses, reacting to informer events instead of polling on a fixed interval.
+func (rc *ResourceConsumer) waitForReplicasEvent(desiredReplicas int, timeout time.Duration, logSuffix string) {
+	if desiredReplicas == rc.GetReplicas() {
+		framework.Logf("%s: current replicas number is equal to desired replicas number%s: %d", rc.kind, logSuffix, desiredReplicas)
+		return
+	}
+
+	lw, objType := rc.replicasListWatch()
+	reached := make(chan struct{})
+	var once sync.Once
+	notifyIfReached := func(obj interface{}) {
+		if replicasOf(obj) == desiredReplicas {
+			once.Do(func() { close(reached) })
 		}
 	}
-	framework.Failf("timeout waiting %v for pods size to be %d", timeout, desiredReplicas)
+	stop := make(chan struct{})
+	defer close(stop)
+	_, controller := cache.NewInformer(lw, objType, 0, cache.ResourceEventHandlerFuncs{
+		AddFunc:    notifyIfReached,
+		UpdateFunc: func(oldObj, newObj interface{}) { notifyIfReached(newObj) },
+	})
+	go controller.Run(stop)
+
+	select {
+	case <-reached:
+		framework.Logf("%s: current replicas number is equal to desired replicas number%s: %d", rc.kind, logSuffix, desiredReplicas)
+	case <-time.After(timeout):
+		framework.Failf("timeout waiting %v for pods size to be %d%s", timeout, desiredReplicas, logSuffix)
+	}
+}
+
+func (rc *ResourceConsumer) WaitForReplicas(desiredReplicas int) {
+	rc.waitForReplicasEvent(desiredReplicas, 15*time.Minute, "")
+}
+
+// WaitForReplicasOnMetric waits until rc's workload has scaled to desiredReplicas, the same
+// way WaitForReplicas does. name only labels the log and failure messages; the custom metric's
+// own value is not read.
+func (rc *ResourceConsumer) WaitForReplicasOnMetric(name string, desiredReplicas int) {
+	rc.waitForReplicasEvent(desiredReplicas, 15*time.Minute, fmt.Sprintf(", driven by metric %s", name))
 }
 
 func (rc *ResourceConsumer) EnsureDesiredReplicas(desiredReplicas int, timeout time.Duration) {
-	for start := time.Now(); time.Since(start) < timeout; time.Sleep(10 * time.Second) {
-		actual := rc.GetReplicas()
-		if desiredReplicas != actual {
-			framework.Failf("Number of replicas has changed: expected %v, got %v", desiredReplicas, actual)
+	// Check the already-current state before installing the informer: the reflector's
+	// initial list-sync delivers it via AddFunc, which we don't otherwise wait on here.
+	if actual := rc.GetReplicas(); actual != desiredReplicas {
+		framework.Failf("Number of replicas has changed: expected %v, got %v", desiredReplicas, actual)
+	}
+
+	lw, objType := rc.replicasListWatch()
+	changed := make(chan int, 1)
+	stop := make(chan struct{})
+	defer close(stop)
+	onReplicasChanged := func(obj interface{}) {
+		if actual := replicasOf(obj); actual != desiredReplicas {
+			select {
+			case changed <- actual:
+			default:
+			}
 		}
-		framework.Logf("Number of replicas is as expected")
 	}
-	framework.Logf("Number of replicas was stable over %v", timeout)
+	_, controller := cache.NewInformer(lw, objType, 0, cache.ResourceEventHandlerFuncs{
+		AddFunc:    onReplicasChanged,
+		UpdateFunc: func(oldObj, newObj interface{}) { onReplicasChanged(newObj) },
+	})
+	go controller.Run(stop)
+
+	select {
+	case actual := <-changed:
+		framework.Failf("Number of replicas has changed: expected %v, got %v", desiredReplicas, actual)
+	case <-time.After(timeout):
+		framework.Logf("Number of replicas was stable over %v", timeout)
+	}
 }
 
 func (rc *ResourceConsumer) CleanUp() {
 	By(fmt.Sprintf("Removing consuming RC %s", rc.name))
 	close(rc.stopCPU)
 	close(rc.stopMem)
-	close(rc.stopCustomMetric)
-	// Wait some time to ensure all child goroutines are finished.
-	time.Sleep(10 * time.Second)
+	rc.customMetricMu.Lock()
+	for _, stopChan := range rc.stopCustomMetric {
+		close(stopChan)
+	}
+	rc.customMetricMu.Unlock()
+	// Wait for the request-sending goroutines to observe the closed stop channels and exit,
+	// so none of them is still sending consumption requests once the workload and services
+	// below are deleted.
+	rc.wg.Wait()
 	framework.ExpectNoError(framework.DeleteRCAndPods(rc.framework.ClientSet, rc.framework.Namespace.Name, rc.name))
 	framework.ExpectNoError(rc.framework.ClientSet.Core().Services(rc.framework.Namespace.Name).Delete(rc.name, nil))
-	framework.ExpectNoError(framework.DeleteRCAndPods(rc.framework.ClientSet, rc.framework.Namespace.Name, rc.controllerName))
-	framework.ExpectNoError(rc.framework.ClientSet.Core().Services(rc.framework.Namespace.Name).Delete(rc.controllerName, nil))
+	framework.ExpectNoError(framework.DeleteRCAndPods(rc.framework.ClientSet, rc.framework.Namespace.Name, rc.controller.name))
+	framework.ExpectNoError(rc.framework.ClientSet.Core().Services(rc.framework.Namespace.Name).Delete(rc.controller.name, nil))
 }
 
-func runServiceAndWorkloadForResourceConsumer(c clientset.Interface, ns, name, kind string, replicas int, cpuLimitMillis, memLimitMb int64) {
+func runServiceAndWorkloadForResourceConsumer(c clientset.Interface, ns, name, kind string, replicas int, cpuLimitMillis, memLimitMb int64) *ResourceConsumerController {
 	By(fmt.Sprintf("Running consuming RC %s via %s with %v replicas", name, kind, replicas))
+	svcSpec := api.ServiceSpec{
+		Ports: []api.ServicePort{{
+			Port:       port,
+			TargetPort: intstr.FromInt(targetPort),
+		}},
+
+		Selector: map[string]string{
+			"name": name,
+		},
+	}
+	if kind == kindStatefulSet {
+		// StatefulSets are addressed through their own headless governing service.
+		svcSpec.ClusterIP = api.ClusterIPNone
+	}
 	_, err := c.Core().Services(ns).Create(&api.Service{
 		ObjectMeta: api.ObjectMeta{
 			Name: name,
 		},
-		Spec: api.ServiceSpec{
-			Ports: []api.ServicePort{{
-				Port:       port,
-				TargetPort: intstr.FromInt(targetPort),
-			}},
-
-			Selector: map[string]string{
-				"name": name,
-			},
-		},
+		Spec: svcSpec,
 	})
 	framework.ExpectNoError(err)
 
@@ -358,13 +553,34 @@ func runServiceAndWorkloadForResourceConsumer(c clientset.Interface, ns, name, k
 		By(fmt.Sprintf("creating replicaset %s in namespace %s", rsConfig.Name, rsConfig.Namespace))
 		framework.ExpectNoError(framework.RunReplicaSet(rsConfig))
 		break
+	case kindStatefulSet:
+		ssConfig := testutils.StatefulSetConfig{
+			RCConfig: rcConfig,
+		}
+		By(fmt.Sprintf("creating statefulset %s in namespace %s", ssConfig.Name, ssConfig.Namespace))
+		framework.ExpectNoError(framework.RunStatefulSet(ssConfig))
+		break
 	default:
 		framework.Failf(invalidKind)
 	}
 
+	return runResourceConsumerController(c, ns, name)
+}
+
+// ResourceConsumerController is the sidecar RC (image resourceConsumerControllerImage)
+// that fans CPU, memory and custom-metric consumption requests out across all replicas
+// of the consumer workload it was started with --consumer-service-name for, instead of
+// a caller having to pick a single consumer pod to hit directly.
+type ResourceConsumerController struct {
+	name string
+}
+
+// runResourceConsumerController starts the controller pod for consumerName's workload and
+// waits for its service to be reachable. The controller is addressed as "consumerName-ctrl".
+func runResourceConsumerController(c clientset.Interface, ns, consumerName string) *ResourceConsumerController {
 	By(fmt.Sprintf("Running controller"))
-	controllerName := name + "-ctrl"
-	_, err = c.Core().Services(ns).Create(&api.Service{
+	controllerName := consumerName + "-ctrl"
+	_, err := c.Core().Services(ns).Create(&api.Service{
 		ObjectMeta: api.ObjectMeta{
 			Name: controllerName,
 		},
@@ -389,7 +605,7 @@ func runServiceAndWorkloadForResourceConsumer(c clientset.Interface, ns, name, k
 		Namespace: ns,
 		Timeout:   timeoutRC,
 		Replicas:  1,
-		Command:   []string{"/controller", "--consumer-service-name=" + name, "--consumer-service-namespace=" + ns, "--consumer-port=80"},
+		Command:   []string{"/controller", "--consumer-service-name=" + consumerName, "--consumer-service-namespace=" + ns, "--consumer-port=80"},
 		DNSPolicy: &dnsClusterFirst,
 	}
 	framework.ExpectNoError(framework.RunRC(controllerRcConfig))
@@ -397,4 +613,6 @@ func runServiceAndWorkloadForResourceConsumer(c clientset.Interface, ns, name, k
 	// Wait for endpoints to propagate for the controller service.
 	framework.ExpectNoError(framework.WaitForServiceEndpointsNum(
 		c, ns, controllerName, 1, startServiceInterval, startServiceTimeout))
+
+	return &ResourceConsumerController{name: controllerName}
 }
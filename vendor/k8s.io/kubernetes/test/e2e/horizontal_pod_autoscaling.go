@@ -0,0 +1,106 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/apis/autoscaling"
+	"k8s.io/kubernetes/test/e2e/framework"
+
+	. "github.com/onsi/ginkgo"
+)
+
+const (
+	memoryTargetUtilizationPercent = 60
+	memoryPodRequestMB             = 250
+	// memoryPodTargetMB is the per-pod memory consumption, in MB, at which average
+	// utilization exactly equals memoryTargetUtilizationPercent of memoryPodRequestMB.
+	// Consuming memoryPodTargetMB*N in total is therefore an equilibrium for N replicas.
+	memoryPodTargetMB = memoryPodRequestMB * memoryTargetUtilizationPercent / 100
+)
+
+// createMemoryHorizontalPodAutoscaler creates an HPA that scales name (a Deployment) between
+// minReplicas and maxReplicas on the average memory utilization of its resourceConsumerImage pods.
+func createMemoryHorizontalPodAutoscaler(f *framework.Framework, name string, minReplicas, maxReplicas int32) {
+	targetUtilization := int32(memoryTargetUtilizationPercent)
+	hpa := &autoscaling.HorizontalPodAutoscaler{
+		ObjectMeta: api.ObjectMeta{
+			Name:      name,
+			Namespace: f.Namespace.Name,
+		},
+		Spec: autoscaling.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscaling.CrossVersionObjectReference{
+				Kind: "Deployment",
+				Name: name,
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: maxReplicas,
+			Metrics: []autoscaling.MetricSpec{
+				{
+					Type: autoscaling.ResourceMetricSourceType,
+					Resource: &autoscaling.ResourceMetricSource{
+						Name: api.ResourceMemory,
+						TargetAverageUtilization: &targetUtilization,
+					},
+				},
+			},
+		},
+	}
+	_, err := f.ClientSet.Autoscaling().HorizontalPodAutoscalers(f.Namespace.Name).Create(hpa)
+	framework.ExpectNoError(err)
+}
+
+var _ = framework.KubeDescribe("[Feature:HPA] Horizontal pod autoscaling (scale resource: Memory)", func() {
+	var rc *ResourceConsumer
+	f := framework.NewDefaultFramework("horizontal-pod-autoscaling")
+
+	AfterEach(func() {
+		rc.CleanUp()
+	})
+
+	framework.KubeDescribe("[Serial] [Slow] Deployment", func() {
+		const (
+			name        = "rc-memory"
+			initPods    = 1
+			initMemMB   = 100
+			minReplicas = 1
+			maxReplicas = 3
+		)
+
+		It("Should scale from 1 pod to 3 pods on memory pressure", func() {
+			rc = NewDynamicResourceConsumer(name, kindDeployment, initPods, 0, initMemMB, nil, 0, memoryPodRequestMB, f)
+			createMemoryHorizontalPodAutoscaler(f, name, minReplicas, maxReplicas)
+
+			rc.WaitForReplicas(initPods)
+
+			By("consuming enough memory per pod to push average utilization above the target")
+			rc.ConsumeMem(maxReplicas * memoryPodTargetMB)
+			rc.WaitForReplicas(maxReplicas)
+		})
+
+		It("Should scale from 3 pods to 1 pod once memory pressure drops", func() {
+			rc = NewDynamicResourceConsumer(name, kindDeployment, maxReplicas, 0, maxReplicas*memoryPodTargetMB, nil, 0, memoryPodRequestMB, f)
+			createMemoryHorizontalPodAutoscaler(f, name, minReplicas, maxReplicas)
+
+			rc.WaitForReplicas(maxReplicas)
+
+			By("dropping memory consumption back down below the target")
+			rc.ConsumeMem(initMemMB)
+			rc.WaitForReplicas(minReplicas)
+		})
+	})
+})